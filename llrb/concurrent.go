@@ -0,0 +1,147 @@
+// Copyright 2010 Petar Maymounkov. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package llrb
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Concurrent wraps an LLRB so that readers never block, and never block
+// each other or a concurrent writer: Get, Has, Min, Max, AscendRange and Len
+// load the current root atomically and operate on that immutable snapshot.
+// Writers are serialized by a mutex; each write clones only the root-to-leaf
+// path it touches (the same path-copying primitives backing Persistent) and
+// publishes the new root with a single atomic store. This gives the
+// ecosystem-standard concurrent ordered map without making readers
+// serialize against each other the way wrapping the whole tree in a
+// sync.RWMutex would.
+type Concurrent struct {
+	root atomic.Pointer[LLRB]
+	mu   sync.Mutex
+}
+
+// NewConcurrent allocates an empty concurrent tree.
+func NewConcurrent() *Concurrent {
+	c := &Concurrent{}
+	c.root.Store(New())
+	return c
+}
+
+// Snapshot returns the tree's current root as a plain *LLRB. It is safe to
+// call any read-only method on the result, including via a long-running
+// AscendRange scan, while other goroutines continue to write to c: the
+// nodes it reaches are never mutated in place by c's own writers, which
+// always clone before changing a node.
+//
+// The returned tree's nodes carry no parent pointers, so an Iterator over
+// it does not visit every element: Next and Prev stop as soon as they would
+// have to climb to a parent.
+//
+// Do not call ReplaceOrInsert, InsertNoReplace, Delete, DeleteMin, or
+// DeleteMax on the returned tree -- those are the in-place *LLRB mutators,
+// not the path-copying ones Concurrent itself uses, and calling them here
+// would corrupt nodes aliased into c's live tree and into any other
+// outstanding snapshot.
+func (c *Concurrent) Snapshot() *LLRB {
+	return c.root.Load()
+}
+
+// Len returns the number of elements in the tree.
+func (c *Concurrent) Len() int {
+	return c.root.Load().Len()
+}
+
+// Has returns true if the tree contains an element whose order is the same as that of key.
+func (c *Concurrent) Has(key Item) bool {
+	return c.root.Load().Has(key)
+}
+
+// Get retrieves an element from the tree whose order is the same as that of key.
+func (c *Concurrent) Get(key Item) Item {
+	return c.root.Load().Get(key)
+}
+
+// Min returns the minimum element in the tree.
+func (c *Concurrent) Min() Item {
+	return c.root.Load().Min()
+}
+
+// Max returns the maximum element in the tree.
+func (c *Concurrent) Max() Item {
+	return c.root.Load().Max()
+}
+
+// AscendRange calls iterator for every item in [greaterOrEqual, lessThan) in
+// ascending order, stopping early if iterator returns false.
+func (c *Concurrent) AscendRange(greaterOrEqual, lessThan Item, iterator func(i Item) bool) {
+	c.root.Load().AscendRange(greaterOrEqual, lessThan, iterator)
+}
+
+// ReplaceOrInsert inserts item into the tree. If an existing element has the
+// same order, it is removed and returned.
+func (c *Concurrent) ReplaceOrInsert(item Item) Item {
+	if item == nil {
+		panic("inserting nil item")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	root, replaced := replaceOrInsertP(c.root.Load().root, item)
+	root.Black = true
+	c.root.Store(&LLRB{root: root})
+	return replaced
+}
+
+// InsertNoReplace inserts item into the tree. If an existing element has the
+// same order, both elements are present afterwards.
+func (c *Concurrent) InsertNoReplace(item Item) {
+	if item == nil {
+		panic("inserting nil item")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	root := insertNoReplaceP(c.root.Load().root, item)
+	root.Black = true
+	c.root.Store(&LLRB{root: root})
+}
+
+// Delete removes the element whose order equals key, returning it, or nil
+// if no such element was present.
+func (c *Concurrent) Delete(key Item) Item {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	root, deleted := deleteP(c.root.Load().root, key)
+	if root != nil {
+		root.Black = true
+	}
+	c.root.Store(&LLRB{root: root})
+	return deleted
+}
+
+// DeleteMin removes the minimum element, returning it, or nil if the tree
+// was empty.
+func (c *Concurrent) DeleteMin() Item {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	root, deleted := deleteMinP(c.root.Load().root)
+	if root != nil {
+		root.Black = true
+	}
+	c.root.Store(&LLRB{root: root})
+	return deleted
+}
+
+// DeleteMax removes the maximum element, returning it, or nil if the tree
+// was empty.
+func (c *Concurrent) DeleteMax() Item {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	root, deleted := deleteMaxP(c.root.Load().root)
+	if root != nil {
+		root.Black = true
+	}
+	c.root.Store(&LLRB{root: root})
+	return deleted
+}
@@ -0,0 +1,109 @@
+// Copyright 2010 Petar Maymounkov. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package llrb
+
+import (
+	"sync"
+	"testing"
+)
+
+type cInt int
+
+func (a cInt) Less(b Item) bool { return a < b.(cInt) }
+
+func TestConcurrentBasic(t *testing.T) {
+	c := NewConcurrent()
+	for _, v := range []int{5, 1, 9, 3, 7} {
+		c.ReplaceOrInsert(cInt(v))
+	}
+	if got, want := c.Len(), 5; got != want {
+		t.Fatalf("Len() = %d, want %d", got, want)
+	}
+	if !c.Has(cInt(3)) {
+		t.Fatalf("Has(3) = false, want true")
+	}
+	if got := c.Min(); got != Item(cInt(1)) {
+		t.Fatalf("Min() = %v, want 1", got)
+	}
+	if got := c.Max(); got != Item(cInt(9)) {
+		t.Fatalf("Max() = %v, want 9", got)
+	}
+	if got := c.Delete(cInt(3)); got != Item(cInt(3)) {
+		t.Fatalf("Delete(3) = %v, want 3", got)
+	}
+	if c.Has(cInt(3)) {
+		t.Fatalf("Has(3) = true after Delete, want false")
+	}
+}
+
+// TestConcurrentSnapshotIsolation confirms that a Snapshot taken before a
+// write is unaffected by that write, the same guarantee Persistent gives.
+func TestConcurrentSnapshotIsolation(t *testing.T) {
+	c := NewConcurrent()
+	for _, v := range []int{10, 20, 30} {
+		c.ReplaceOrInsert(cInt(v))
+	}
+	snap := c.Snapshot()
+
+	c.ReplaceOrInsert(cInt(40))
+	c.Delete(cInt(10))
+
+	if snap.Len() != 3 {
+		t.Fatalf("snapshot.Len() = %d, want 3", snap.Len())
+	}
+	if !snap.Has(cInt(10)) {
+		t.Fatalf("snapshot lost element 10 present when it was taken")
+	}
+	if snap.Has(cInt(40)) {
+		t.Fatalf("snapshot observed element 40 written after it was taken")
+	}
+	if c.Len() != 3 {
+		t.Fatalf("c.Len() = %d, want 3 after inserting 40 and deleting 10", c.Len())
+	}
+}
+
+// TestConcurrentRace exercises concurrent readers and a writer under the
+// race detector: readers never block, and never observe a torn root.
+func TestConcurrentRace(t *testing.T) {
+	c := NewConcurrent()
+	for i := 0; i < 100; i++ {
+		c.ReplaceOrInsert(cInt(i))
+	}
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				c.Has(cInt(50))
+				c.Len()
+				c.Min()
+				c.Max()
+				c.AscendRange(cInt(10), cInt(20), func(i Item) bool { return true })
+				_ = c.Snapshot()
+			}
+		}()
+	}
+
+	for i := 100; i < 300; i++ {
+		c.ReplaceOrInsert(cInt(i))
+	}
+	for i := 0; i < 100; i++ {
+		c.Delete(cInt(i))
+	}
+	c.DeleteMin()
+	c.DeleteMax()
+
+	close(stop)
+	wg.Wait()
+}
@@ -0,0 +1,403 @@
+// Copyright 2010 Petar Maymounkov. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package generic is a type-parameterized Left-Leaning Red-Black (LLRB)
+// implementation of 2-3 balanced binary search trees, keyed by a
+// user-supplied comparator rather than the llrb.Item interface. It is
+// rebalanced with the same rbcore primitives as the sibling llrb package,
+// specialized here to function-typed comparators so that ordered key/value
+// pairs can be stored without the interface boxing and dynamic Less
+// dispatch that the Item-based LLRB requires.
+package generic
+
+import "github.com/JonathanLogan/GoLLRB/llrb/rbcore"
+
+// Tree is an LLRB tree of key/value pairs ordered by less.
+type Tree[K any, V any] struct {
+	root *node[K, V]
+	less func(a, b K) bool
+}
+
+type node[K any, V any] struct {
+	key         K
+	val         V
+	Left, Right *node[K, V] // Pointers to left and right child nodes
+	parent      *node[K, V] // Pointer to the parent node, nil at the root
+	count       int
+	Black       bool // If set, the color of the link (incoming from the parent) is black
+	// In the LLRB, new nodes are always red, hence the zero-value for node
+}
+
+// Accessor methods satisfying rbcore.Node[node[K, V]], so that the
+// rotate/flip/moveRed/fixUp primitives are the same implementation shared
+// with the Item-boxed llrb.LLRB, rather than a second copy of the same
+// logic.
+func (h *node[K, V]) GetLeft() *node[K, V]    { return h.Left }
+func (h *node[K, V]) SetLeft(n *node[K, V])   { h.Left = n }
+func (h *node[K, V]) GetRight() *node[K, V]   { return h.Right }
+func (h *node[K, V]) SetRight(n *node[K, V])  { h.Right = n }
+func (h *node[K, V]) GetParent() *node[K, V]  { return h.parent }
+func (h *node[K, V]) SetParent(n *node[K, V]) { h.parent = n }
+func (h *node[K, V]) IsBlack() bool           { return h.Black }
+func (h *node[K, V]) SetBlack(b bool)         { h.Black = b }
+func (h *node[K, V]) GetCount() int           { return h.count }
+func (h *node[K, V]) SetCount(c int)          { h.count = c }
+
+// New allocates a new tree ordered by less.
+func New[K any, V any](less func(a, b K) bool) *Tree[K, V] {
+	return &Tree[K, V]{less: less}
+}
+
+// Ordered is the subset of comparable types usable with NewOrdered.
+type Ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 | ~string
+}
+
+// NewOrdered allocates a new tree ordered by the natural "<" order of K.
+func NewOrdered[K Ordered, V any]() *Tree[K, V] {
+	return New[K, V](func(a, b K) bool { return a < b })
+}
+
+// Len returns the number of elements in the tree.
+func (t *Tree[K, V]) Len() int {
+	if t.root != nil {
+		return t.root.count
+	}
+	return 0
+}
+
+// Get retrieves the value stored under k, if any.
+func (t *Tree[K, V]) Get(k K) (V, bool) {
+	h := t.root
+	for h != nil {
+		switch {
+		case t.less(k, h.key):
+			h = h.Left
+		case t.less(h.key, k):
+			h = h.Right
+		default:
+			return h.val, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// Min returns the smallest key in the tree and its value.
+func (t *Tree[K, V]) Min() (K, V, bool) {
+	h := t.root
+	if h == nil {
+		var zk K
+		var zv V
+		return zk, zv, false
+	}
+	for h.Left != nil {
+		h = h.Left
+	}
+	return h.key, h.val, true
+}
+
+// Max returns the largest key in the tree and its value.
+func (t *Tree[K, V]) Max() (K, V, bool) {
+	h := t.root
+	if h == nil {
+		var zk K
+		var zv V
+		return zk, zv, false
+	}
+	for h.Right != nil {
+		h = h.Right
+	}
+	return h.key, h.val, true
+}
+
+// Put inserts (k, v) into the tree. If k is already present, its value is
+// replaced and the old value is returned with replaced set to true.
+func (t *Tree[K, V]) Put(k K, v V) (old V, replaced bool) {
+	var oldItem V
+	var wasReplaced bool
+	t.root, oldItem, wasReplaced = t.put(t.root, k, v)
+	t.root.Black = true
+	t.root.parent = nil
+	return oldItem, wasReplaced
+}
+
+func (t *Tree[K, V]) put(h *node[K, V], k K, v V) (*node[K, V], V, bool) {
+	if h == nil {
+		return newNode(k, v), *new(V), false
+	}
+
+	var old V
+	var replaced bool
+	if t.less(k, h.key) {
+		h.Left, old, replaced = t.put(h.Left, k, v)
+		h.Left.parent = h
+		if !replaced {
+			h.count++
+		}
+	} else if t.less(h.key, k) {
+		h.Right, old, replaced = t.put(h.Right, k, v)
+		h.Right.parent = h
+		if !replaced {
+			h.count++
+		}
+	} else {
+		old, h.val, replaced = h.val, v, true
+	}
+
+	return h.walkUpRot23(), old, replaced
+}
+
+// Delete removes the element keyed by k, returning its value if present.
+func (t *Tree[K, V]) Delete(k K) (V, bool) {
+	var deleted V
+	var ok bool
+	t.root, deleted, ok = t.delete(t.root, k)
+	if t.root != nil {
+		t.root.Black = true
+		t.root.parent = nil
+	}
+	return deleted, ok
+}
+
+func (t *Tree[K, V]) delete(h *node[K, V], k K) (*node[K, V], V, bool) {
+	var deleted V
+	var ok bool
+	if h == nil {
+		return nil, deleted, false
+	}
+
+	if t.less(k, h.key) {
+		if h.Left == nil { // key not present. Nothing to delete
+			return h, deleted, false
+		}
+		if !h.Left.isRed() && !h.Left.Left.isRed() {
+			h = h.moveRedLeft()
+		}
+		h.Left, deleted, ok = t.delete(h.Left, k)
+		if h.Left != nil {
+			h.Left.parent = h
+		}
+	} else {
+		if h.Left.isRed() {
+			h = h.rotateRight()
+		}
+		if !t.less(h.key, k) && h.Right == nil {
+			return nil, h.val, true
+		}
+		if h.Right != nil {
+			if !h.Right.isRed() && !h.Right.Left.isRed() {
+				h = h.moveRedRight()
+			}
+			if !t.less(h.key, k) {
+				var subKey K
+				var subVal V
+				h.Right, subKey, subVal = h.Right.deleteMin()
+				if h.Right != nil {
+					h.Right.parent = h
+				}
+				deleted, h.key, h.val = h.val, subKey, subVal
+				ok = true
+			} else {
+				h.Right, deleted, ok = t.delete(h.Right, k)
+				if h.Right != nil {
+					h.Right.parent = h
+				}
+			}
+		}
+	}
+	if ok {
+		h.count--
+	}
+
+	return h.fixUp(), deleted, ok
+}
+
+// deleteMin removes the minimum-keyed node from the subtree rooted at h,
+// returning the resulting subtree and the removed key/value.
+func (h *node[K, V]) deleteMin() (*node[K, V], K, V) {
+	if h.Left == nil {
+		return nil, h.key, h.val
+	}
+
+	if !h.Left.isRed() && !h.Left.Left.isRed() {
+		h = h.moveRedLeft()
+	}
+
+	var k K
+	var v V
+	h.Left, k, v = h.Left.deleteMin()
+	if h.Left != nil {
+		h.Left.parent = h
+	}
+	h.count--
+
+	return h.fixUp(), k, v
+}
+
+// AscendRange calls iterator for every key in the tree within
+// [greaterOrEqual, lessThan) in ascending order, stopping early if iterator
+// returns false.
+func (t *Tree[K, V]) AscendRange(greaterOrEqual, lessThan K, iterator func(k K, v V) bool) {
+	ascendRange(t.root, t.less, greaterOrEqual, lessThan, iterator)
+}
+
+func ascendRange[K any, V any](h *node[K, V], less func(a, b K) bool, greaterOrEqual, lessThan K, iterator func(k K, v V) bool) bool {
+	if h == nil {
+		return true
+	}
+	if less(h.key, greaterOrEqual) {
+		return ascendRange(h.Right, less, greaterOrEqual, lessThan, iterator)
+	}
+	if !ascendRange(h.Left, less, greaterOrEqual, lessThan, iterator) {
+		return false
+	}
+	if !less(h.key, lessThan) {
+		return false
+	}
+	if !iterator(h.key, h.val) {
+		return false
+	}
+	return ascendRange(h.Right, less, greaterOrEqual, lessThan, iterator)
+}
+
+// Iterator walks a Tree in key order using the node's parent pointers,
+// stepping to the next or previous element in O(1) amortized time per move.
+// It is meant for use cases such as merging sorted streams or resumable
+// range scans, where a closure-based visitor is awkward.
+//
+// An Iterator is invalidated by any mutation of the tree it was created
+// from; using one after a subsequent Put or Delete has undefined results.
+type Iterator[K any, V any] struct {
+	t   *Tree[K, V]
+	cur *node[K, V]
+}
+
+// NewIterator returns an Iterator over t, initially invalid; call SeekMin,
+// SeekMax, or Seek before using it.
+func NewIterator[K any, V any](t *Tree[K, V]) *Iterator[K, V] {
+	return &Iterator[K, V]{t: t}
+}
+
+// Valid reports whether the iterator is positioned at an element.
+func (it *Iterator[K, V]) Valid() bool {
+	return it.cur != nil
+}
+
+// Key returns the key the iterator is positioned at. It panics if the
+// iterator is not Valid.
+func (it *Iterator[K, V]) Key() K {
+	return it.cur.key
+}
+
+// Value returns the value the iterator is positioned at. It panics if the
+// iterator is not Valid.
+func (it *Iterator[K, V]) Value() V {
+	return it.cur.val
+}
+
+// SeekMin positions the iterator at the minimum element of the tree.
+func (it *Iterator[K, V]) SeekMin() {
+	h := it.t.root
+	if h == nil {
+		it.cur = nil
+		return
+	}
+	for h.Left != nil {
+		h = h.Left
+	}
+	it.cur = h
+}
+
+// SeekMax positions the iterator at the maximum element of the tree.
+func (it *Iterator[K, V]) SeekMax() {
+	h := it.t.root
+	if h == nil {
+		it.cur = nil
+		return
+	}
+	for h.Right != nil {
+		h = h.Right
+	}
+	it.cur = h
+}
+
+// Seek positions the iterator at the element keyed by k. If no such element
+// exists, the iterator becomes invalid.
+func (it *Iterator[K, V]) Seek(k K) {
+	h := it.t.root
+	for h != nil {
+		switch {
+		case it.t.less(k, h.key):
+			h = h.Left
+		case it.t.less(h.key, k):
+			h = h.Right
+		default:
+			it.cur = h
+			return
+		}
+	}
+	it.cur = nil
+}
+
+// Next advances the iterator to the next element in order. It is a no-op if
+// the iterator is not Valid.
+func (it *Iterator[K, V]) Next() {
+	if it.cur == nil {
+		return
+	}
+	if it.cur.Right != nil {
+		h := it.cur.Right
+		for h.Left != nil {
+			h = h.Left
+		}
+		it.cur = h
+		return
+	}
+	h, p := it.cur, it.cur.parent
+	for p != nil && h == p.Right {
+		h, p = p, p.parent
+	}
+	it.cur = p
+}
+
+// Prev moves the iterator to the previous element in order. It is a no-op
+// if the iterator is not Valid.
+func (it *Iterator[K, V]) Prev() {
+	if it.cur == nil {
+		return
+	}
+	if it.cur.Left != nil {
+		h := it.cur.Left
+		for h.Right != nil {
+			h = h.Right
+		}
+		it.cur = h
+		return
+	}
+	h, p := it.cur, it.cur.parent
+	for p != nil && h == p.Left {
+		h, p = p, p.parent
+	}
+	it.cur = p
+}
+
+// Internal node manipulation routines. These delegate to rbcore so that the
+// generic tree rebalances through the same implementation as the sibling
+// llrb package.
+
+func newNode[K any, V any](k K, v V) *node[K, V] { return &node[K, V]{key: k, val: v, count: 1} }
+
+func (h *node[K, V]) isRed() bool { return rbcore.IsRed[node[K, V]](h) }
+
+func (h *node[K, V]) rotateLeft() *node[K, V]  { return rbcore.RotateLeft[node[K, V]](h) }
+func (h *node[K, V]) rotateRight() *node[K, V] { return rbcore.RotateRight[node[K, V]](h) }
+func (h *node[K, V]) flip()                    { rbcore.Flip[node[K, V]](h) }
+
+func (h *node[K, V]) walkUpRot23() *node[K, V]  { return rbcore.WalkUpRot23[node[K, V]](h) }
+func (h *node[K, V]) moveRedLeft() *node[K, V]  { return rbcore.MoveRedLeft[node[K, V]](h) }
+func (h *node[K, V]) moveRedRight() *node[K, V] { return rbcore.MoveRedRight[node[K, V]](h) }
+func (h *node[K, V]) fixUp() *node[K, V]        { return rbcore.FixUp[node[K, V]](h) }
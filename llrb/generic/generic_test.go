@@ -0,0 +1,274 @@
+// Copyright 2010 Petar Maymounkov. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package generic
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBasic(t *testing.T) {
+	tr := NewOrdered[int, string]()
+	if _, ok := tr.Get(1); ok {
+		t.Fatalf("Get on empty tree found a value")
+	}
+
+	if old, replaced := tr.Put(1, "a"); replaced || old != "" {
+		t.Fatalf("Put(1, a) = %q, %v, want \"\", false", old, replaced)
+	}
+	if old, replaced := tr.Put(1, "b"); !replaced || old != "a" {
+		t.Fatalf("Put(1, b) = %q, %v, want \"a\", true", old, replaced)
+	}
+	if got, ok := tr.Get(1); !ok || got != "b" {
+		t.Fatalf("Get(1) = %q, %v, want \"b\", true", got, ok)
+	}
+	if tr.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", tr.Len())
+	}
+
+	if v, ok := tr.Delete(1); !ok || v != "b" {
+		t.Fatalf("Delete(1) = %q, %v, want \"b\", true", v, ok)
+	}
+	if tr.Len() != 0 {
+		t.Fatalf("Len() = %d, want 0 after deleting the only element", tr.Len())
+	}
+	if _, ok := tr.Delete(1); ok {
+		t.Fatalf("Delete(1) on a now-empty tree reported success")
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	tr := NewOrdered[int, int]()
+	if _, _, ok := tr.Min(); ok {
+		t.Fatalf("Min on empty tree reported a value")
+	}
+	if _, _, ok := tr.Max(); ok {
+		t.Fatalf("Max on empty tree reported a value")
+	}
+
+	for _, k := range []int{5, 1, 9, 3, 7} {
+		tr.Put(k, k*10)
+	}
+	if k, v, ok := tr.Min(); !ok || k != 1 || v != 10 {
+		t.Fatalf("Min() = %d, %d, %v, want 1, 10, true", k, v, ok)
+	}
+	if k, v, ok := tr.Max(); !ok || k != 9 || v != 90 {
+		t.Fatalf("Max() = %d, %d, %v, want 9, 90, true", k, v, ok)
+	}
+}
+
+func TestAscendRange(t *testing.T) {
+	tr := NewOrdered[int, int]()
+	for _, k := range []int{20, 40, 60, 80, 100, 10, 30, 50, 70, 90} {
+		tr.Put(k, k)
+	}
+
+	var got []int
+	tr.AscendRange(30, 80, func(k, v int) bool {
+		got = append(got, k)
+		return true
+	})
+	want := []int{30, 40, 50, 60, 70}
+	if len(got) != len(want) {
+		t.Fatalf("AscendRange visited %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("AscendRange visited %v, want %v", got, want)
+		}
+	}
+
+	var count int
+	tr.AscendRange(0, 1000, func(k, v int) bool {
+		count++
+		return count < 3
+	})
+	if count != 3 {
+		t.Fatalf("AscendRange did not stop early: visited %d items, want 3", count)
+	}
+}
+
+// checkParents confirms that every node's parent pointer agrees with how it
+// is actually reached from the root.
+func checkParents[K any, V any](t *testing.T, h, parent *node[K, V]) {
+	if h == nil {
+		return
+	}
+	if h.parent != parent {
+		t.Fatalf("node %v has parent %v, want %v", h.key, h.parent, parent)
+	}
+	checkParents(t, h.Left, h)
+	checkParents(t, h.Right, h)
+}
+
+func TestIterator(t *testing.T) {
+	tr := NewOrdered[int, int]()
+	values := []int{50, 30, 70, 20, 40, 60, 80, 10, 90, 25}
+	for _, v := range values {
+		tr.Put(v, v)
+	}
+	checkParents(t, tr.root, nil)
+
+	sorted := append([]int(nil), values...)
+	for i := 1; i < len(sorted); i++ {
+		for j := i; j > 0 && sorted[j-1] > sorted[j]; j-- {
+			sorted[j-1], sorted[j] = sorted[j], sorted[j-1]
+		}
+	}
+
+	it := NewIterator(tr)
+	it.SeekMin()
+	var forward []int
+	for it.Valid() {
+		forward = append(forward, it.Key())
+		it.Next()
+	}
+	if len(forward) != len(sorted) {
+		t.Fatalf("forward walk visited %v, want %v", forward, sorted)
+	}
+	for i := range sorted {
+		if forward[i] != sorted[i] {
+			t.Fatalf("forward walk visited %v, want %v", forward, sorted)
+		}
+	}
+
+	it.SeekMax()
+	var backward []int
+	for it.Valid() {
+		backward = append(backward, it.Key())
+		it.Prev()
+	}
+	for i := range sorted {
+		if backward[i] != sorted[len(sorted)-1-i] {
+			t.Fatalf("backward walk visited %v, want reverse of %v", backward, sorted)
+		}
+	}
+
+	it.Seek(40)
+	if !it.Valid() || it.Key() != 40 || it.Value() != 40 {
+		t.Fatalf("Seek(40): key=%d, value=%d, valid=%v", it.Key(), it.Value(), it.Valid())
+	}
+	it.Seek(41)
+	if it.Valid() {
+		t.Fatalf("Seek(41) on a tree without 41: got valid iterator at %d", it.Key())
+	}
+
+	tr.Delete(30)
+	checkParents(t, tr.root, nil)
+}
+
+// TestAgainstMap fuzzes Tree against a map[int]int reference: the same
+// sequence of randomized Put/Delete calls is applied to both, and after
+// about one mutation in fifty, chosen at random, the tree's Len, Get,
+// Min/Max, AscendRange, and Iterator walk are checked for agreement with
+// the reference.
+func TestAgainstMap(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tr := NewOrdered[int, int]()
+	ref := map[int]int{}
+
+	const ops = 20000
+	const keySpace = 200
+
+	for i := 0; i < ops; i++ {
+		k := rng.Intn(keySpace)
+		v := rng.Int()
+
+		if rng.Intn(3) == 0 {
+			delete(ref, k)
+			tr.Delete(k)
+		} else {
+			ref[k] = v
+			tr.Put(k, v)
+		}
+
+		if rng.Intn(50) != 0 {
+			continue
+		}
+
+		if tr.Len() != len(ref) {
+			t.Fatalf("op %d: Len() = %d, want %d", i, tr.Len(), len(ref))
+		}
+		for k, want := range ref {
+			if got, ok := tr.Get(k); !ok || got != want {
+				t.Fatalf("op %d: Get(%d) = %d, %v, want %d, true", i, k, got, ok, want)
+			}
+		}
+		checkMinMax(t, i, tr, ref)
+		checkOrderedWalk(t, i, tr, ref)
+	}
+}
+
+func checkMinMax(t *testing.T, op int, tr *Tree[int, int], ref map[int]int) {
+	if len(ref) == 0 {
+		if _, _, ok := tr.Min(); ok {
+			t.Fatalf("op %d: Min() reported a value on an empty tree", op)
+		}
+		return
+	}
+	minKey, maxKey := minMaxKeys(ref)
+	if k, _, ok := tr.Min(); !ok || k != minKey {
+		t.Fatalf("op %d: Min() key = %d, %v, want %d, true", op, k, ok, minKey)
+	}
+	if k, _, ok := tr.Max(); !ok || k != maxKey {
+		t.Fatalf("op %d: Max() key = %d, %v, want %d, true", op, k, ok, maxKey)
+	}
+}
+
+func minMaxKeys(ref map[int]int) (min, max int) {
+	first := true
+	for k := range ref {
+		if first || k < min {
+			min = k
+		}
+		if first || k > max {
+			max = k
+		}
+		first = false
+	}
+	return min, max
+}
+
+// checkOrderedWalk confirms that AscendRange over the full key space and a
+// forward Iterator walk both produce ref's keys in ascending order.
+func checkOrderedWalk(t *testing.T, op int, tr *Tree[int, int], ref map[int]int) {
+	sorted := make([]int, 0, len(ref))
+	for k := range ref {
+		sorted = append(sorted, k)
+	}
+	for i := 1; i < len(sorted); i++ {
+		for j := i; j > 0 && sorted[j-1] > sorted[j]; j-- {
+			sorted[j-1], sorted[j] = sorted[j], sorted[j-1]
+		}
+	}
+
+	var viaRange []int
+	tr.AscendRange(-1<<31, 1<<31-1, func(k, v int) bool {
+		viaRange = append(viaRange, k)
+		return true
+	})
+	if len(viaRange) != len(sorted) {
+		t.Fatalf("op %d: AscendRange visited %d keys, want %d", op, len(viaRange), len(sorted))
+	}
+	for i := range sorted {
+		if viaRange[i] != sorted[i] {
+			t.Fatalf("op %d: AscendRange visited %v, want %v", op, viaRange, sorted)
+		}
+	}
+
+	var viaIter []int
+	it := NewIterator(tr)
+	for it.SeekMin(); it.Valid(); it.Next() {
+		viaIter = append(viaIter, it.Key())
+	}
+	if len(viaIter) != len(sorted) {
+		t.Fatalf("op %d: Iterator visited %d keys, want %d", op, len(viaIter), len(sorted))
+	}
+	for i := range sorted {
+		if viaIter[i] != sorted[i] {
+			t.Fatalf("op %d: Iterator visited %v, want %v", op, viaIter, sorted)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2010 Petar Maymounkov. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package llrb
+
+// Iterator walks an LLRB tree in key order using the node's parent pointers,
+// stepping to the next or previous element in O(1) amortized time per move.
+// It is meant for use cases such as merging sorted streams, computing set
+// intersections, or resumable range scans, where a closure-based visitor is
+// awkward.
+//
+// An Iterator is invalidated by any mutation of the tree it was created
+// from; using one after a subsequent ReplaceOrInsert, InsertNoReplace,
+// Delete, DeleteMin, or DeleteMax has undefined results.
+type Iterator struct {
+	t   *LLRB
+	cur *Node
+}
+
+// NewIterator returns an Iterator over t, initially invalid; call SeekMin,
+// SeekMax, or Seek before using it.
+func NewIterator(t *LLRB) *Iterator {
+	return &Iterator{t: t}
+}
+
+// Valid reports whether the iterator is positioned at an element.
+func (it *Iterator) Valid() bool {
+	return it.cur != nil
+}
+
+// Item returns the element the iterator is positioned at. It panics if the
+// iterator is not Valid.
+func (it *Iterator) Item() Item {
+	return it.cur.Item
+}
+
+// SeekMin positions the iterator at the minimum element of the tree.
+func (it *Iterator) SeekMin() {
+	h := it.t.root
+	if h == nil {
+		it.cur = nil
+		return
+	}
+	for h.Left != nil {
+		h = h.Left
+	}
+	it.cur = h
+}
+
+// SeekMax positions the iterator at the maximum element of the tree.
+func (it *Iterator) SeekMax() {
+	h := it.t.root
+	if h == nil {
+		it.cur = nil
+		return
+	}
+	for h.Right != nil {
+		h = h.Right
+	}
+	it.cur = h
+}
+
+// Seek positions the iterator at the element whose order is the same as
+// that of key. If no such element exists, the iterator becomes invalid.
+func (it *Iterator) Seek(key Item) {
+	h := it.t.root
+	for h != nil {
+		switch {
+		case less(key, h.Item):
+			h = h.Left
+		case less(h.Item, key):
+			h = h.Right
+		default:
+			it.cur = h
+			return
+		}
+	}
+	it.cur = nil
+}
+
+// Next advances the iterator to the next element in order. It is a no-op if
+// the iterator is not Valid.
+func (it *Iterator) Next() {
+	if it.cur == nil {
+		return
+	}
+	if it.cur.Right != nil {
+		h := it.cur.Right
+		for h.Left != nil {
+			h = h.Left
+		}
+		it.cur = h
+		return
+	}
+	h, p := it.cur, it.cur.parent
+	for p != nil && h == p.Right {
+		h, p = p, p.parent
+	}
+	it.cur = p
+}
+
+// Prev moves the iterator to the previous element in order. It is a no-op
+// if the iterator is not Valid.
+func (it *Iterator) Prev() {
+	if it.cur == nil {
+		return
+	}
+	if it.cur.Left != nil {
+		h := it.cur.Left
+		for h.Right != nil {
+			h = h.Right
+		}
+		it.cur = h
+		return
+	}
+	h, p := it.cur, it.cur.parent
+	for p != nil && h == p.Left {
+		h, p = p, p.parent
+	}
+	it.cur = p
+}
@@ -0,0 +1,109 @@
+// Copyright 2010 Petar Maymounkov. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package llrb
+
+import "testing"
+
+type iInt int
+
+func (a iInt) Less(b Item) bool { return a < b.(iInt) }
+
+// checkParents confirms that every node's parent pointer agrees with how it
+// is actually reached from the root, recursing into h's subtree.
+func checkParents(t *testing.T, h, parent *Node) {
+	if h == nil {
+		return
+	}
+	if h.parent != parent {
+		t.Fatalf("node %v has parent %v, want %v", h.Item, h.parent, parent)
+	}
+	checkParents(t, h.Left, h)
+	checkParents(t, h.Right, h)
+}
+
+func TestIteratorParentMaintenance(t *testing.T) {
+	tr := New()
+	for _, v := range []int{50, 30, 70, 20, 40, 60, 80, 10, 90, 25} {
+		tr.ReplaceOrInsert(iInt(v))
+	}
+	checkParents(t, tr.root, nil)
+
+	tr.Delete(iInt(30))
+	checkParents(t, tr.root, nil)
+
+	tr.DeleteMin()
+	checkParents(t, tr.root, nil)
+
+	tr.DeleteMax()
+	checkParents(t, tr.root, nil)
+}
+
+func TestIteratorForwardBackward(t *testing.T) {
+	tr := New()
+	values := []int{50, 30, 70, 20, 40, 60, 80, 10, 90, 25}
+	for _, v := range values {
+		tr.ReplaceOrInsert(iInt(v))
+	}
+
+	sorted := append([]int(nil), values...)
+	for i := 1; i < len(sorted); i++ {
+		for j := i; j > 0 && sorted[j-1] > sorted[j]; j-- {
+			sorted[j-1], sorted[j] = sorted[j], sorted[j-1]
+		}
+	}
+
+	it := NewIterator(tr)
+	it.SeekMin()
+	var forward []int
+	for it.Valid() {
+		forward = append(forward, int(it.Item().(iInt)))
+		it.Next()
+	}
+	if len(forward) != len(sorted) {
+		t.Fatalf("forward walk visited %v, want %v", forward, sorted)
+	}
+	for i := range sorted {
+		if forward[i] != sorted[i] {
+			t.Fatalf("forward walk visited %v, want %v", forward, sorted)
+		}
+	}
+
+	it.SeekMax()
+	var backward []int
+	for it.Valid() {
+		backward = append(backward, int(it.Item().(iInt)))
+		it.Prev()
+	}
+	if len(backward) != len(sorted) {
+		t.Fatalf("backward walk visited %v, want reverse of %v", backward, sorted)
+	}
+	for i := range sorted {
+		if backward[i] != sorted[len(sorted)-1-i] {
+			t.Fatalf("backward walk visited %v, want reverse of %v", backward, sorted)
+		}
+	}
+}
+
+func TestIteratorSeek(t *testing.T) {
+	tr := New()
+	for _, v := range []int{10, 20, 30, 40, 50} {
+		tr.ReplaceOrInsert(iInt(v))
+	}
+
+	it := NewIterator(tr)
+	it.Seek(iInt(30))
+	if !it.Valid() || it.Item() != Item(iInt(30)) {
+		t.Fatalf("Seek(30): got %v, valid=%v", it.Item(), it.Valid())
+	}
+	it.Next()
+	if !it.Valid() || it.Item() != Item(iInt(40)) {
+		t.Fatalf("Next() after Seek(30): got %v, valid=%v", it.Item(), it.Valid())
+	}
+
+	it.Seek(iInt(25))
+	if it.Valid() {
+		t.Fatalf("Seek(25) on a tree without 25: got valid iterator at %v", it.Item())
+	}
+}
@@ -16,6 +16,8 @@
 //
 package llrb
 
+import "github.com/JonathanLogan/GoLLRB/llrb/rbcore"
+
 // Tree is a Left-Leaning Red-Black (LLRB) implementation of 2-3 trees
 type LLRB struct {
 	root *Node
@@ -24,6 +26,7 @@ type LLRB struct {
 type Node struct {
 	Item
 	Left, Right *Node // Pointers to left and right child nodes
+	parent      *Node // Pointer to the parent node, nil at the root
 	count       int
 	Black       bool // If set, the color of the link (incoming from the parent) is black
 	// In the LLRB, new nodes are always red, hence the zero-value for node
@@ -165,6 +168,7 @@ func (t *LLRB) ReplaceOrInsert(item Item) Item {
 	var replaced Item
 	t.root, replaced = t.replaceOrInsert(t.root, item)
 	t.root.Black = true
+	t.root.parent = nil
 	return replaced
 }
 
@@ -178,11 +182,13 @@ func (t *LLRB) replaceOrInsert(h *Node, item Item) (*Node, Item) {
 	var replaced Item
 	if less(item, h.Item) { // BUG
 		h.Left, replaced = t.replaceOrInsert(h.Left, item)
+		h.Left.parent = h
 		if replaced == nil {
 			h.count++
 		}
 	} else if less(h.Item, item) {
 		h.Right, replaced = t.replaceOrInsert(h.Right, item)
+		h.Right.parent = h
 		if replaced == nil {
 			h.count++
 		}
@@ -203,6 +209,7 @@ func (t *LLRB) InsertNoReplace(item Item) {
 	}
 	t.root = t.insertNoReplace(t.root, item)
 	t.root.Black = true
+	t.root.parent = nil
 }
 
 func (t *LLRB) insertNoReplace(h *Node, item Item) *Node {
@@ -214,8 +221,10 @@ func (t *LLRB) insertNoReplace(h *Node, item Item) *Node {
 
 	if less(item, h.Item) {
 		h.Left = t.insertNoReplace(h.Left, item)
+		h.Left.parent = h
 	} else {
 		h.Right = t.insertNoReplace(h.Right, item)
+		h.Right.parent = h
 	}
 	h.count++
 
@@ -271,6 +280,7 @@ func (t *LLRB) DeleteMin() Item {
 	t.root, deleted = t.root.deleteMin()
 	if t.root != nil {
 		t.root.Black = true
+		t.root.parent = nil
 	}
 	return deleted
 }
@@ -290,6 +300,9 @@ func (h *Node) deleteMin() (*Node, Item) {
 
 	var deleted Item
 	h.Left, deleted = h.Left.deleteMin()
+	if h.Left != nil {
+		h.Left.parent = h
+	}
 	if deleted != nil {
 		h.count--
 	}
@@ -304,6 +317,7 @@ func (t *LLRB) DeleteMax() Item {
 	t.root, deleted = t.root.deleteMax()
 	if t.root != nil {
 		t.root.Black = true
+		t.root.parent = nil
 	}
 	return deleted
 }
@@ -323,6 +337,9 @@ func (h *Node) deleteMax() (*Node, Item) {
 	}
 	var deleted Item
 	h.Right, deleted = h.Right.deleteMax()
+	if h.Right != nil {
+		h.Right.parent = h
+	}
 	if deleted != nil {
 		h.count--
 	}
@@ -337,6 +354,7 @@ func (t *LLRB) Delete(key Item) Item {
 	t.root, deleted = t.delete(t.root, key)
 	if t.root != nil {
 		t.root.Black = true
+		t.root.parent = nil
 	}
 	return deleted
 }
@@ -354,6 +372,9 @@ func (t *LLRB) delete(h *Node, item Item) (*Node, Item) {
 			h = h.moveRedLeft()
 		}
 		h.Left, deleted = t.delete(h.Left, item)
+		if h.Left != nil {
+			h.Left.parent = h
+		}
 	} else {
 		if h.Left.isRed() {
 			h = h.rotateRight()
@@ -373,12 +394,18 @@ func (t *LLRB) delete(h *Node, item Item) (*Node, Item) {
 			if !less(h.Item, item) {
 				var subDeleted Item
 				h.Right, subDeleted = h.Right.deleteMin()
+				if h.Right != nil {
+					h.Right.parent = h
+				}
 				if subDeleted == nil {
 					panic("logic")
 				}
 				deleted, h.Item = h.Item, subDeleted
 			} else { // Else, @item is bigger than @h.Item
 				h.Right, deleted = t.delete(h.Right, item)
+				if h.Right != nil {
+					h.Right.parent = h
+				}
 			}
 		}
 	}
@@ -393,93 +420,24 @@ func (t *LLRB) delete(h *Node, item Item) (*Node, Item) {
 
 func newNode(item Item) *Node { return &Node{Item: item, count: 1} }
 
-func (h *Node) isRed() bool {
-	if h == nil {
-		return false
-	}
-	return !h.Black
-}
-
-func (h *Node) rotateLeft() *Node {
-	x := h.Right
-	if x.Black {
-		panic("rotating a black link")
-	}
-
-	x.count, h.count = h.count, h.count-x.count
-	if x.Left != nil {
-		h.count += x.Left.count
-	}
-
-	h.Right = x.Left
-	x.Left = h
-	x.Black = h.Black
-	h.Black = false
-	return x
-}
-
-func (h *Node) rotateRight() *Node {
-	x := h.Left
-	if x.Black {
-		panic("rotating a black link")
-	}
-
-	x.count, h.count = h.count, h.count-x.count
-	if x.Right != nil {
-		h.count += x.Right.count
-	}
-
-	h.Left = x.Right
-	x.Right = h
-	x.Black = h.Black
-	h.Black = false
-	return x
-}
-
-// REQUIRE: Left and Right children must be present
-func (h *Node) flip() {
-	h.Black = !h.Black
-	if h.Left != nil {
-		h.Left.Black = !h.Left.Black
-	}
-	if h.Right != nil {
-		h.Right.Black = !h.Right.Black
-	}
-}
-
-// REQUIRE: Left and Right children must be present
-func (h *Node) moveRedLeft() *Node {
-	h.flip()
-	if h.Right != nil && h.Right.Left.isRed() {
-		h.Right = h.Right.rotateRight()
-		h = h.rotateLeft()
-		h.flip()
-	}
-	return h
-}
-
-// REQUIRE: Left and Right children must be present
-func (h *Node) moveRedRight() *Node {
-	h.flip()
-	if h.Left != nil && h.Left.Left.isRed() {
-		h = h.rotateRight()
-		h.flip()
-	}
-	return h
-}
-
-func (h *Node) fixUp() *Node {
-	if h.Right.isRed() {
-		h = h.rotateLeft()
-	}
-
-	if h.Left.isRed() && h.Left.Left.isRed() {
-		h = h.rotateRight()
-	}
-
-	if h.Left.isRed() && h.Right.isRed() {
-		h.flip()
-	}
-
-	return h
-}
+// Accessor methods satisfying rbcore.Node[Node], so that the rotate/flip/
+// moveRed/fixUp primitives below are the same implementation shared with
+// llrb/generic's Tree, rather than a second copy of the same logic.
+func (h *Node) GetLeft() *Node    { return h.Left }
+func (h *Node) SetLeft(n *Node)   { h.Left = n }
+func (h *Node) GetRight() *Node   { return h.Right }
+func (h *Node) SetRight(n *Node)  { h.Right = n }
+func (h *Node) GetParent() *Node  { return h.parent }
+func (h *Node) SetParent(n *Node) { h.parent = n }
+func (h *Node) IsBlack() bool     { return h.Black }
+func (h *Node) SetBlack(b bool)   { h.Black = b }
+func (h *Node) GetCount() int     { return h.count }
+func (h *Node) SetCount(c int)    { h.count = c }
+
+func (h *Node) isRed() bool         { return rbcore.IsRed[Node](h) }
+func (h *Node) rotateLeft() *Node   { return rbcore.RotateLeft[Node](h) }
+func (h *Node) rotateRight() *Node  { return rbcore.RotateRight[Node](h) }
+func (h *Node) flip()               { rbcore.Flip[Node](h) }
+func (h *Node) moveRedLeft() *Node  { return rbcore.MoveRedLeft[Node](h) }
+func (h *Node) moveRedRight() *Node { return rbcore.MoveRedRight[Node](h) }
+func (h *Node) fixUp() *Node        { return rbcore.FixUp[Node](h) }
@@ -0,0 +1,383 @@
+// Copyright 2010 Petar Maymounkov. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package llrb
+
+// Persistent is an immutable, path-copying variant of LLRB. Every mutating
+// method returns a new *Persistent reflecting the update; the receiver and
+// any tree derived from it earlier remain valid and unaffected. Subtrees
+// that are not on the root-to-leaf path of a given update are shared between
+// the old and new versions, so an update costs O(log N) time and space
+// rather than O(N). Because existing versions never change, a *Persistent
+// may be read from multiple goroutines concurrently without locking.
+type Persistent struct {
+	root *Node
+}
+
+// NewPersistent allocates an empty persistent tree.
+func NewPersistent() *Persistent {
+	return &Persistent{}
+}
+
+// Len returns the number of nodes in the tree.
+func (t *Persistent) Len() int {
+	if t.root != nil {
+		return t.root.count
+	}
+	return 0
+}
+
+// Has returns true if the tree contains an element whose order is the same as that of key.
+func (t *Persistent) Has(key Item) bool {
+	return t.Get(key) != nil
+}
+
+// Get retrieves an element from the tree whose order is the same as that of key.
+func (t *Persistent) Get(key Item) Item {
+	h := t.root
+	for h != nil {
+		switch {
+		case less(key, h.Item):
+			h = h.Left
+		case less(h.Item, key):
+			h = h.Right
+		default:
+			return h.Item
+		}
+	}
+	return nil
+}
+
+// Min returns the minimum element in the tree.
+func (t *Persistent) Min() Item {
+	h := t.root
+	if h == nil {
+		return nil
+	}
+	for h.Left != nil {
+		h = h.Left
+	}
+	return h.Item
+}
+
+// Max returns the maximum element in the tree.
+func (t *Persistent) Max() Item {
+	h := t.root
+	if h == nil {
+		return nil
+	}
+	for h.Right != nil {
+		h = h.Right
+	}
+	return h.Item
+}
+
+// ReplaceOrInsert returns a new tree with item inserted. If an existing
+// element has the same order, it is removed from the new tree and returned;
+// the receiver is left untouched.
+func (t *Persistent) ReplaceOrInsert(item Item) (*Persistent, Item) {
+	if item == nil {
+		panic("inserting nil item")
+	}
+	root, replaced := replaceOrInsertP(t.root, item)
+	root.Black = true
+	return &Persistent{root: root}, replaced
+}
+
+// InsertNoReplace returns a new tree with item inserted. If an existing
+// element has the same order, both elements are present in the new tree.
+func (t *Persistent) InsertNoReplace(item Item) *Persistent {
+	if item == nil {
+		panic("inserting nil item")
+	}
+	root := insertNoReplaceP(t.root, item)
+	root.Black = true
+	return &Persistent{root: root}
+}
+
+// Delete returns a new tree with the element whose order equals key removed,
+// along with the removed item, or nil if no such element was present.
+func (t *Persistent) Delete(key Item) (*Persistent, Item) {
+	root, deleted := deleteP(t.root, key)
+	if root != nil {
+		root.Black = true
+	}
+	return &Persistent{root: root}, deleted
+}
+
+// DeleteMin returns a new tree with the minimum element removed, along with
+// the removed item, or nil if the tree was empty.
+func (t *Persistent) DeleteMin() (*Persistent, Item) {
+	root, deleted := deleteMinP(t.root)
+	if root != nil {
+		root.Black = true
+	}
+	return &Persistent{root: root}, deleted
+}
+
+// DeleteMax returns a new tree with the maximum element removed, along with
+// the removed item, or nil if the tree was empty.
+func (t *Persistent) DeleteMax() (*Persistent, Item) {
+	root, deleted := deleteMaxP(t.root)
+	if root != nil {
+		root.Black = true
+	}
+	return &Persistent{root: root}, deleted
+}
+
+// cloneNode returns a shallow copy of h, or nil if h is nil. The copy shares
+// h's children until the caller relinks them, which is what gives
+// path-copying its structural sharing. The clone's parent pointer is left
+// nil: a shared subtree can hang off more than one parent across versions,
+// so a single parent field on the node itself cannot describe it, and
+// Persistent does not expose an Iterator that would need it.
+func cloneNode(h *Node) *Node {
+	if h == nil {
+		return nil
+	}
+	c := *h
+	c.parent = nil
+	return &c
+}
+
+// The functions below mirror replaceOrInsert/insertNoReplace/delete/deleteMin/
+// deleteMax and their rotation helpers in llrb.go, with one difference: every
+// node whose fields are about to change is cloned first, so the original
+// tree is never mutated. Unvisited subtrees are left aliased.
+
+func replaceOrInsertP(h *Node, item Item) (*Node, Item) {
+	if h == nil {
+		return newNode(item), nil
+	}
+	h = cloneNode(h)
+
+	var replaced Item
+	if less(item, h.Item) {
+		h.Left, replaced = replaceOrInsertP(h.Left, item)
+		if replaced == nil {
+			h.count++
+		}
+	} else if less(h.Item, item) {
+		h.Right, replaced = replaceOrInsertP(h.Right, item)
+		if replaced == nil {
+			h.count++
+		}
+	} else {
+		replaced, h.Item = h.Item, item
+	}
+
+	return h.walkUpRot23P(), replaced
+}
+
+func insertNoReplaceP(h *Node, item Item) *Node {
+	if h == nil {
+		return newNode(item)
+	}
+	h = cloneNode(h)
+
+	if less(item, h.Item) {
+		h.Left = insertNoReplaceP(h.Left, item)
+	} else {
+		h.Right = insertNoReplaceP(h.Right, item)
+	}
+	h.count++
+
+	return h.walkUpRot23P()
+}
+
+func (h *Node) walkUpRot23P() *Node {
+	if h.Right.isRed() && !h.Left.isRed() {
+		h = h.rotateLeftP()
+	}
+	if h.Left.isRed() && h.Left.Left.isRed() {
+		h = h.rotateRightP()
+	}
+	if h.Left.isRed() && h.Right.isRed() {
+		h = h.flipP()
+	}
+	return h
+}
+
+func deleteMinP(h *Node) (*Node, Item) {
+	if h == nil {
+		return nil, nil
+	}
+	h = cloneNode(h)
+	if h.Left == nil {
+		return nil, h.Item
+	}
+
+	if !h.Left.isRed() && !h.Left.Left.isRed() {
+		h = h.moveRedLeftP()
+	}
+
+	var deleted Item
+	h.Left, deleted = deleteMinP(h.Left)
+	if deleted != nil {
+		h.count--
+	}
+
+	return h.fixUpP(), deleted
+}
+
+func deleteMaxP(h *Node) (*Node, Item) {
+	if h == nil {
+		return nil, nil
+	}
+	h = cloneNode(h)
+	if h.Left.isRed() {
+		h = h.rotateRightP()
+	}
+	if h.Right == nil {
+		return nil, h.Item
+	}
+	if !h.Right.isRed() && !h.Right.Left.isRed() {
+		h = h.moveRedRightP()
+	}
+	var deleted Item
+	h.Right, deleted = deleteMaxP(h.Right)
+	if deleted != nil {
+		h.count--
+	}
+
+	return h.fixUpP(), deleted
+}
+
+func deleteP(h *Node, item Item) (*Node, Item) {
+	var deleted Item
+	if h == nil {
+		return nil, nil
+	}
+	h = cloneNode(h)
+
+	if less(item, h.Item) {
+		if h.Left == nil { // item not present. Nothing to delete
+			return h, nil
+		}
+		if !h.Left.isRed() && !h.Left.Left.isRed() {
+			h = h.moveRedLeftP()
+		}
+		h.Left, deleted = deleteP(h.Left, item)
+	} else {
+		if h.Left.isRed() {
+			h = h.rotateRightP()
+		}
+		if !less(h.Item, item) && h.Right == nil {
+			return nil, h.Item
+		}
+		if h.Right != nil {
+			if !h.Right.isRed() && !h.Right.Left.isRed() {
+				h = h.moveRedRightP()
+			}
+			if !less(h.Item, item) {
+				var subDeleted Item
+				h.Right, subDeleted = deleteMinP(h.Right)
+				if subDeleted == nil {
+					panic("logic")
+				}
+				deleted, h.Item = h.Item, subDeleted
+			} else {
+				h.Right, deleted = deleteP(h.Right, item)
+			}
+		}
+	}
+	if deleted != nil {
+		h.count--
+	}
+
+	return h.fixUpP(), deleted
+}
+
+// Persistent rotation/flip/move-red helpers. Every call site hands these a
+// receiver that was already cloned moments earlier in the same call chain
+// (replaceOrInsertP, insertNoReplaceP, deleteMinP, deleteMaxP and deleteP
+// each clone h before recursing), so these helpers only need to clone the
+// child they are about to promote or touch for the first time.
+
+func (h *Node) rotateLeftP() *Node {
+	x := cloneNode(h.Right)
+	if x.Black {
+		panic("rotating a black link")
+	}
+
+	x.count, h.count = h.count, h.count-x.count
+	if x.Left != nil {
+		h.count += x.Left.count
+	}
+
+	h.Right = x.Left
+	x.Left = h
+	x.Black = h.Black
+	h.Black = false
+	return x
+}
+
+func (h *Node) rotateRightP() *Node {
+	x := cloneNode(h.Left)
+	if x.Black {
+		panic("rotating a black link")
+	}
+
+	x.count, h.count = h.count, h.count-x.count
+	if x.Right != nil {
+		h.count += x.Right.count
+	}
+
+	h.Left = x.Right
+	x.Right = h
+	x.Black = h.Black
+	h.Black = false
+	return x
+}
+
+// REQUIRE: Left and Right children must be present
+func (h *Node) flipP() *Node {
+	h.Black = !h.Black
+	if h.Left != nil {
+		h.Left = cloneNode(h.Left)
+		h.Left.Black = !h.Left.Black
+	}
+	if h.Right != nil {
+		h.Right = cloneNode(h.Right)
+		h.Right.Black = !h.Right.Black
+	}
+	return h
+}
+
+// REQUIRE: Left and Right children must be present
+func (h *Node) moveRedLeftP() *Node {
+	h = h.flipP()
+	if h.Right != nil && h.Right.Left.isRed() {
+		// flipP has already replaced h.Right with a clone, so this second
+		// clone is redundant; rotateRightP then changes only private nodes.
+		h.Right = cloneNode(h.Right)
+		h.Right = h.Right.rotateRightP()
+		h = h.rotateLeftP()
+		h = h.flipP()
+	}
+	return h
+}
+
+// REQUIRE: Left and Right children must be present
+func (h *Node) moveRedRightP() *Node {
+	h = h.flipP()
+	if h.Left != nil && h.Left.Left.isRed() {
+		h = h.rotateRightP()
+		h = h.flipP()
+	}
+	return h
+}
+
+func (h *Node) fixUpP() *Node {
+	if h.Right.isRed() {
+		h = h.rotateLeftP()
+	}
+	if h.Left.isRed() && h.Left.Left.isRed() {
+		h = h.rotateRightP()
+	}
+	if h.Left.isRed() && h.Right.isRed() {
+		h = h.flipP()
+	}
+	return h
+}
@@ -0,0 +1,73 @@
+// Copyright 2010 Petar Maymounkov. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package llrb
+
+import (
+	"reflect"
+	"testing"
+)
+
+type pInt int
+
+func (a pInt) Less(b Item) bool { return a < b.(pInt) }
+
+// enumerate returns every item of t in ascending order.
+func enumerate(t *Persistent) []Item {
+	var got []Item
+	var walk func(h *Node)
+	walk = func(h *Node) {
+		if h == nil {
+			return
+		}
+		walk(h.Left)
+		got = append(got, h.Item)
+		walk(h.Right)
+	}
+	walk(t.root)
+	return got
+}
+
+// TestPersistentSnapshotIsolation confirms that a snapshot taken before an
+// update still enumerates its original elements after arbitrary follow-up
+// mutations to trees derived from it.
+func TestPersistentSnapshotIsolation(t *testing.T) {
+	base := NewPersistent()
+	for _, v := range []int{5, 1, 9, 3, 7, 2, 8, 4, 6, 0} {
+		base, _ = base.ReplaceOrInsert(pInt(v))
+	}
+	snapshot := base
+	want := enumerate(snapshot)
+
+	// Arbitrary follow-up mutations against trees derived from base,
+	// none of which should be visible through the earlier snapshot.
+	next, _ := base.Delete(pInt(5))
+	next, _ = next.DeleteMin()
+	next, _ = next.DeleteMax()
+	next = next.InsertNoReplace(pInt(1))
+	for _, v := range []int{42, 100, 101, 102} {
+		next, _ = next.ReplaceOrInsert(pInt(v))
+	}
+
+	if got := enumerate(snapshot); !reflect.DeepEqual(got, want) {
+		t.Fatalf("snapshot enumeration changed after follow-up mutations: got %v, want %v", got, want)
+	}
+	if !snapshot.Has(pInt(5)) {
+		t.Fatalf("snapshot lost element 5 present at the time it was taken")
+	}
+	if snapshot.Has(pInt(42)) {
+		t.Fatalf("snapshot observed element 42 inserted only into a later version")
+	}
+	if got, want := snapshot.Len(), len(want); got != want {
+		t.Fatalf("snapshot.Len() = %d, want %d", got, want)
+	}
+
+	// The later version should reflect every mutation applied to it.
+	if next.Has(pInt(5)) {
+		t.Fatalf("later version still has element 5, which it deleted")
+	}
+	if !next.Has(pInt(42)) {
+		t.Fatalf("later version missing element 42, which it inserted")
+	}
+}
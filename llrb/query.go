@@ -0,0 +1,149 @@
+// Copyright 2010 Petar Maymounkov. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package llrb
+
+// Floor returns the largest item less than or equal to key, or nil if no
+// such item exists.
+func (t *LLRB) Floor(key Item) Item {
+	var candidate Item
+	h := t.root
+	for h != nil {
+		switch {
+		case less(key, h.Item):
+			h = h.Left
+		case less(h.Item, key):
+			candidate = h.Item
+			h = h.Right
+		default:
+			return h.Item
+		}
+	}
+	return candidate
+}
+
+// Ceiling returns the smallest item greater than or equal to key, or nil if
+// no such item exists.
+func (t *LLRB) Ceiling(key Item) Item {
+	var candidate Item
+	h := t.root
+	for h != nil {
+		switch {
+		case less(key, h.Item):
+			candidate = h.Item
+			h = h.Left
+		case less(h.Item, key):
+			h = h.Right
+		default:
+			return h.Item
+		}
+	}
+	return candidate
+}
+
+// Predecessor returns the largest item strictly less than key, or nil if no
+// such item exists.
+func (t *LLRB) Predecessor(key Item) Item {
+	var candidate Item
+	h := t.root
+	for h != nil {
+		if less(h.Item, key) {
+			candidate = h.Item
+			h = h.Right
+		} else {
+			h = h.Left
+		}
+	}
+	return candidate
+}
+
+// Successor returns the smallest item strictly greater than key, or nil if
+// no such item exists.
+func (t *LLRB) Successor(key Item) Item {
+	var candidate Item
+	h := t.root
+	for h != nil {
+		if less(key, h.Item) {
+			candidate = h.Item
+			h = h.Left
+		} else {
+			h = h.Right
+		}
+	}
+	return candidate
+}
+
+// Rank returns the number of items in the tree strictly less than key.
+func (t *LLRB) Rank(key Item) int {
+	return rank(t.root, key)
+}
+
+func rank(h *Node, key Item) int {
+	if h == nil {
+		return 0
+	}
+	switch {
+	case less(h.Item, key):
+		return subtreeCount(h.Left) + 1 + rank(h.Right, key)
+	case less(key, h.Item):
+		return rank(h.Left, key)
+	default:
+		return subtreeCount(h.Left)
+	}
+}
+
+// Select returns the i-th smallest item in the tree (0-indexed), or nil if
+// i is out of range.
+func (t *LLRB) Select(i int) Item {
+	if i < 0 || i >= t.Len() {
+		return nil
+	}
+	return selectNode(t.root, i).Item
+}
+
+func selectNode(h *Node, i int) *Node {
+	left := subtreeCount(h.Left)
+	switch {
+	case i < left:
+		return selectNode(h.Left, i)
+	case i > left:
+		return selectNode(h.Right, i-left-1)
+	default:
+		return h
+	}
+}
+
+// subtreeCount returns the size of the subtree rooted at h, or 0 if h is nil.
+func subtreeCount(h *Node) int {
+	if h == nil {
+		return 0
+	}
+	return h.count
+}
+
+// AscendRange calls iterator for every item in the tree within
+// [greaterOrEqual, lessThan) in ascending order, stopping early if iterator
+// returns false.
+func (t *LLRB) AscendRange(greaterOrEqual, lessThan Item, iterator func(i Item) bool) {
+	ascendRange(t.root, greaterOrEqual, lessThan, iterator)
+}
+
+func ascendRange(h *Node, greaterOrEqual, lessThan Item, iterator func(i Item) bool) bool {
+	if h == nil {
+		return true
+	}
+	if less(h.Item, greaterOrEqual) {
+		return ascendRange(h.Right, greaterOrEqual, lessThan, iterator)
+	}
+	if !ascendRange(h.Left, greaterOrEqual, lessThan, iterator) {
+		return false
+	}
+	if !less(h.Item, lessThan) {
+		return false
+	}
+	if !iterator(h.Item) {
+		return false
+	}
+	return ascendRange(h.Right, greaterOrEqual, lessThan, iterator)
+}
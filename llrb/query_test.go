@@ -0,0 +1,119 @@
+// Copyright 2010 Petar Maymounkov. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package llrb
+
+import "testing"
+
+type qInt int
+
+func (a qInt) Less(b Item) bool { return a < b.(qInt) }
+
+func buildQueryTree() *LLRB {
+	t := New()
+	for _, v := range []int{20, 40, 60, 80, 100, 10, 30, 50, 70, 90} {
+		t.ReplaceOrInsert(qInt(v))
+	}
+	return t
+}
+
+func TestFloorCeiling(t *testing.T) {
+	tr := buildQueryTree()
+
+	cases := []struct {
+		key         int
+		floor, ceil Item
+	}{
+		{5, nil, qInt(10)},
+		{10, qInt(10), qInt(10)},
+		{15, qInt(10), qInt(20)},
+		{100, qInt(100), qInt(100)},
+		{105, qInt(100), nil},
+	}
+	for _, c := range cases {
+		if got := tr.Floor(qInt(c.key)); got != c.floor {
+			t.Errorf("Floor(%d) = %v, want %v", c.key, got, c.floor)
+		}
+		if got := tr.Ceiling(qInt(c.key)); got != c.ceil {
+			t.Errorf("Ceiling(%d) = %v, want %v", c.key, got, c.ceil)
+		}
+	}
+}
+
+func TestPredecessorSuccessor(t *testing.T) {
+	tr := buildQueryTree()
+
+	cases := []struct {
+		key                    int
+		predecessor, successor Item
+	}{
+		{5, nil, qInt(10)},
+		{10, nil, qInt(20)},
+		{15, qInt(10), qInt(20)},
+		{100, qInt(90), nil},
+		{105, qInt(100), nil},
+	}
+	for _, c := range cases {
+		if got := tr.Predecessor(qInt(c.key)); got != c.predecessor {
+			t.Errorf("Predecessor(%d) = %v, want %v", c.key, got, c.predecessor)
+		}
+		if got := tr.Successor(qInt(c.key)); got != c.successor {
+			t.Errorf("Successor(%d) = %v, want %v", c.key, got, c.successor)
+		}
+	}
+}
+
+func TestRankSelect(t *testing.T) {
+	tr := buildQueryTree()
+	sorted := []int{10, 20, 30, 40, 50, 60, 70, 80, 90, 100}
+
+	for i, v := range sorted {
+		if got := tr.Rank(qInt(v)); got != i {
+			t.Errorf("Rank(%d) = %d, want %d", v, got, i)
+		}
+		if got := tr.Select(i); got != qInt(v) {
+			t.Errorf("Select(%d) = %v, want %v", i, got, v)
+		}
+	}
+	if got := tr.Rank(qInt(5)); got != 0 {
+		t.Errorf("Rank(5) = %d, want 0", got)
+	}
+	if got := tr.Rank(qInt(105)); got != len(sorted) {
+		t.Errorf("Rank(105) = %d, want %d", got, len(sorted))
+	}
+	if got := tr.Select(-1); got != nil {
+		t.Errorf("Select(-1) = %v, want nil", got)
+	}
+	if got := tr.Select(len(sorted)); got != nil {
+		t.Errorf("Select(%d) = %v, want nil", len(sorted), got)
+	}
+}
+
+func TestAscendRange(t *testing.T) {
+	tr := buildQueryTree()
+
+	var got []int
+	tr.AscendRange(qInt(30), qInt(80), func(i Item) bool {
+		got = append(got, int(i.(qInt)))
+		return true
+	})
+	want := []int{30, 40, 50, 60, 70}
+	if len(got) != len(want) {
+		t.Fatalf("AscendRange visited %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("AscendRange visited %v, want %v", got, want)
+		}
+	}
+
+	var count int
+	tr.AscendRange(qInt(0), qInt(1000), func(i Item) bool {
+		count++
+		return count < 3
+	})
+	if count != 3 {
+		t.Fatalf("AscendRange did not stop early: visited %d items, want 3", count)
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2010 Petar Maymounkov. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package rbcore holds the node-manipulation primitives shared by every
+// Left-Leaning Red-Black tree in this module: the rotate/flip/moveRed/fixUp
+// routines that rebalance a 2-3 tree after an insert or delete, and the
+// parent-pointer bookkeeping that a bidirectional Iterator needs. They are
+// written once here, against a minimal structural contract (Node[N]), so
+// that the Item-boxed llrb.LLRB and the type-parameterized generic.Tree
+// both rebalance through the same battle-tested implementation instead of
+// maintaining two copies that can drift apart.
+//
+// A concrete node type does not need to know about rbcore's existence
+// beyond implementing Node[N]; ordering/comparison and payload storage
+// stay with the caller, since those are the parts that legitimately differ
+// between an Item-boxed tree and a key/value one. The caller is also
+// responsible for linking a freshly returned node into its own parent
+// slot (and setting that node's parent pointer) after calling into this
+// package, the same way it must already update its own Left/Right fields;
+// rbcore only maintains parent links for relinking it performs itself
+// (rotations).
+package rbcore
+
+// Node is the structural contract a tree node must satisfy to be
+// rebalanced by this package: get/set its children and parent, its link
+// color, and the size of the subtree rooted at it. N is the concrete node
+// struct; *N is expected to implement this interface, which is expressed
+// as the type constraint "*N" below.
+type Node[N any] interface {
+	*N
+	GetLeft() *N
+	SetLeft(*N)
+	GetRight() *N
+	SetRight(*N)
+	GetParent() *N
+	SetParent(*N)
+	IsBlack() bool
+	SetBlack(bool)
+	GetCount() int
+	SetCount(int)
+}
+
+// IsRed reports whether h carries a red incoming link. A nil node is
+// considered black, matching the LLRB convention that missing links are
+// black.
+func IsRed[N any, P Node[N]](h P) bool {
+	if h == nil {
+		return false
+	}
+	return !h.IsBlack()
+}
+
+// RotateLeft performs a left rotation around h, promoting h.GetRight() to
+// the top of the subtree. The caller is responsible for setting the
+// returned node's parent once it is linked into its own parent's slot.
+func RotateLeft[N any, P Node[N]](h P) P {
+	x := P(h.GetRight())
+	if x.IsBlack() {
+		panic("rotating a black link")
+	}
+
+	hc, xc := h.GetCount(), x.GetCount()
+	x.SetCount(hc)
+	h.SetCount(hc - xc)
+	if xl := P(x.GetLeft()); xl != nil {
+		h.SetCount(h.GetCount() + xl.GetCount())
+	}
+
+	h.SetRight(x.GetLeft())
+	if r := P(h.GetRight()); r != nil {
+		r.SetParent(h)
+	}
+	x.SetLeft(h)
+	h.SetParent(x)
+	x.SetBlack(h.IsBlack())
+	h.SetBlack(false)
+	return x
+}
+
+// RotateRight performs a right rotation around h, promoting h.GetLeft() to
+// the top of the subtree. The caller is responsible for setting the
+// returned node's parent once it is linked into its own parent's slot.
+func RotateRight[N any, P Node[N]](h P) P {
+	x := P(h.GetLeft())
+	if x.IsBlack() {
+		panic("rotating a black link")
+	}
+
+	hc, xc := h.GetCount(), x.GetCount()
+	x.SetCount(hc)
+	h.SetCount(hc - xc)
+	if xr := P(x.GetRight()); xr != nil {
+		h.SetCount(h.GetCount() + xr.GetCount())
+	}
+
+	h.SetLeft(x.GetRight())
+	if l := P(h.GetLeft()); l != nil {
+		l.SetParent(h)
+	}
+	x.SetRight(h)
+	h.SetParent(x)
+	x.SetBlack(h.IsBlack())
+	h.SetBlack(false)
+	return x
+}
+
+// Flip reverses the color of h and both of its children.
+// REQUIRE: Left and Right children must be present.
+func Flip[N any, P Node[N]](h P) {
+	h.SetBlack(!h.IsBlack())
+	if l := P(h.GetLeft()); l != nil {
+		l.SetBlack(!l.IsBlack())
+	}
+	if r := P(h.GetRight()); r != nil {
+		r.SetBlack(!r.IsBlack())
+	}
+}
+
+// WalkUpRot23 restores the 2-3 invariant on the way back up the insertion
+// path: it fixes a right-leaning red link, then a double left-leaning red
+// link, then splits a temporary 4-node by flipping colors.
+func WalkUpRot23[N any, P Node[N]](h P) P {
+	if IsRed[N](P(h.GetRight())) && !IsRed[N](P(h.GetLeft())) {
+		h = RotateLeft[N](h)
+	}
+	if l := P(h.GetLeft()); IsRed[N](l) && IsRed[N](P(l.GetLeft())) {
+		h = RotateRight[N](h)
+	}
+	if IsRed[N](P(h.GetLeft())) && IsRed[N](P(h.GetRight())) {
+		Flip[N](h)
+	}
+	return h
+}
+
+// MoveRedLeft borrows a node from h's right sibling, or fuses with it, so
+// that a delete can safely recurse into h's left child.
+// REQUIRE: Left and Right children must be present.
+func MoveRedLeft[N any, P Node[N]](h P) P {
+	Flip[N](h)
+	if r := P(h.GetRight()); r != nil && IsRed[N](P(r.GetLeft())) {
+		nr := RotateRight[N](r)
+		h.SetRight(nr)
+		nr.SetParent(h)
+		h = RotateLeft[N](h)
+		Flip[N](h)
+	}
+	return h
+}
+
+// MoveRedRight borrows a node from h's left sibling, or fuses with it, so
+// that a delete can safely recurse into h's right child.
+// REQUIRE: Left and Right children must be present.
+func MoveRedRight[N any, P Node[N]](h P) P {
+	Flip[N](h)
+	if l := P(h.GetLeft()); l != nil && IsRed[N](P(l.GetLeft())) {
+		h = RotateRight[N](h)
+		Flip[N](h)
+	}
+	return h
+}
+
+// FixUp restores the 2-3 invariant after a delete has rearranged h's
+// subtree.
+func FixUp[N any, P Node[N]](h P) P {
+	if IsRed[N](P(h.GetRight())) {
+		h = RotateLeft[N](h)
+	}
+	if l := P(h.GetLeft()); IsRed[N](l) && IsRed[N](P(l.GetLeft())) {
+		h = RotateRight[N](h)
+	}
+	if IsRed[N](P(h.GetLeft())) && IsRed[N](P(h.GetRight())) {
+		Flip[N](h)
+	}
+	return h
+}
@@ -0,0 +1,227 @@
+// Copyright 2010 Petar Maymounkov. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package llrb
+
+import (
+	"io"
+	"iter"
+)
+
+// MarshalBinary writes t to w as a pre-order traversal: one bit per node
+// marking its presence, one bit for its color, and the item itself encoded
+// by enc. Reconstructing from this format does not require rebalancing,
+// since the on-disk shape is already a valid LLRB; see UnmarshalBinary.
+func (t *LLRB) MarshalBinary(w io.Writer, enc func(io.Writer, Item) error) error {
+	bw := newBitWriter(w)
+	if err := marshalNode(t.root, bw, enc); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func marshalNode(h *Node, bw *bitWriter, enc func(io.Writer, Item) error) error {
+	if h == nil {
+		return bw.WriteBit(0)
+	}
+	if err := bw.WriteBit(1); err != nil {
+		return err
+	}
+	color := 0
+	if !h.Black {
+		color = 1
+	}
+	if err := bw.WriteBit(color); err != nil {
+		return err
+	}
+	// enc writes directly to the underlying io.Writer, so align to a byte
+	// boundary before handing control to it.
+	if err := bw.Align(); err != nil {
+		return err
+	}
+	if err := enc(bw.w, h.Item); err != nil {
+		return err
+	}
+	if err := marshalNode(h.Left, bw, enc); err != nil {
+		return err
+	}
+	return marshalNode(h.Right, bw, enc)
+}
+
+// UnmarshalBinary reconstructs a tree previously written by MarshalBinary,
+// decoding each item with dec. Nodes are built directly from the wire
+// format and count is recomputed bottom-up; no rotations are performed.
+func UnmarshalBinary(r io.Reader, dec func(io.Reader) (Item, error)) (*LLRB, error) {
+	br := newBitReader(r)
+	root, err := unmarshalNode(br, dec)
+	if err != nil {
+		return nil, err
+	}
+	return &LLRB{root: root}, nil
+}
+
+func unmarshalNode(br *bitReader, dec func(io.Reader) (Item, error)) (*Node, error) {
+	present, err := br.ReadBit()
+	if err != nil {
+		return nil, err
+	}
+	if present == 0 {
+		return nil, nil
+	}
+	color, err := br.ReadBit()
+	if err != nil {
+		return nil, err
+	}
+	if err := br.Align(); err != nil {
+		return nil, err
+	}
+	item, err := dec(br.r)
+	if err != nil {
+		return nil, err
+	}
+	left, err := unmarshalNode(br, dec)
+	if err != nil {
+		return nil, err
+	}
+	right, err := unmarshalNode(br, dec)
+	if err != nil {
+		return nil, err
+	}
+	h := &Node{Item: item, Left: left, Right: right, Black: color == 0, count: 1}
+	if left != nil {
+		left.parent = h
+		h.count += left.count
+	}
+	if right != nil {
+		right.parent = h
+		h.count += right.count
+	}
+	return h, nil
+}
+
+// bitWriter packs single bits into bytes written to an underlying io.Writer.
+type bitWriter struct {
+	w   io.Writer
+	buf byte
+	n   uint8
+}
+
+func newBitWriter(w io.Writer) *bitWriter { return &bitWriter{w: w} }
+
+func (bw *bitWriter) WriteBit(b int) error {
+	bw.buf |= byte(b&1) << bw.n
+	bw.n++
+	if bw.n == 8 {
+		return bw.flushByte()
+	}
+	return nil
+}
+
+func (bw *bitWriter) flushByte() error {
+	_, err := bw.w.Write([]byte{bw.buf})
+	bw.buf, bw.n = 0, 0
+	return err
+}
+
+// Align pads and flushes any partial byte so that the next write to w starts
+// on a byte boundary.
+func (bw *bitWriter) Align() error {
+	if bw.n == 0 {
+		return nil
+	}
+	return bw.flushByte()
+}
+
+// Flush writes out any buffered bits, padding the final byte with zeros.
+func (bw *bitWriter) Flush() error { return bw.Align() }
+
+// bitReader is the mirror image of bitWriter.
+type bitReader struct {
+	r   io.Reader
+	buf byte
+	n   uint8
+}
+
+func newBitReader(r io.Reader) *bitReader { return &bitReader{r: r} }
+
+func (br *bitReader) ReadBit() (int, error) {
+	if br.n == 0 {
+		var b [1]byte
+		if _, err := io.ReadFull(br.r, b[:]); err != nil {
+			return 0, err
+		}
+		br.buf = b[0]
+		br.n = 8
+	}
+	bit := int(br.buf & 1)
+	br.buf >>= 1
+	br.n--
+	return bit, nil
+}
+
+// Align discards any unread bits from the current byte, so that the next
+// read from r starts on a byte boundary.
+func (br *bitReader) Align() error {
+	br.n = 0
+	return nil
+}
+
+// BulkLoadSorted builds a perfectly balanced LLRB from items, which must
+// already be in ascending order, in O(N) time, avoiding the O(N log N) cost
+// of repeated ReplaceOrInsert calls. It first lays out a complete binary
+// tree with the nodes of its deepest level colored red when that level is
+// incomplete (a complete tree is all black), the same computeRedLevel coloring used to bulk-load standard
+// red-black trees; that alone can leave a red link leaning right, which
+// this package's LLRB forbids, so each node is then passed through the
+// same walkUpRot23 rebalancer used by ReplaceOrInsert to fix any such lean
+// before its result is handed to the parent. The result is a tree with
+// every root-to-leaf path carrying the same black-height.
+func BulkLoadSorted(items iter.Seq[Item]) *LLRB {
+	var sorted []Item
+	for it := range items {
+		sorted = append(sorted, it)
+	}
+	redLevel := computeRedLevel(len(sorted))
+	root := buildBalanced(sorted, 0, len(sorted)-1, 0, redLevel)
+	if root != nil {
+		root.Black = true
+		root.parent = nil
+	}
+	return &LLRB{root: root}
+}
+
+// computeRedLevel returns the number of complete levels in a minimal-height
+// binary tree holding size nodes, which is the 0-based depth of the level
+// just below the deepest complete one: the level holding the leftover nodes
+// when the tree is incomplete, and a depth no node reaches when it is
+// complete.
+func computeRedLevel(size int) int {
+	level := 0
+	for m := size - 1; m >= 0; m = m/2 - 1 {
+		level++
+	}
+	return level
+}
+
+// buildBalanced builds a balanced subtree from sorted[lo:hi+1]. level is the
+// depth of the node being built.
+func buildBalanced(sorted []Item, lo, hi, level, redLevel int) *Node {
+	if lo > hi {
+		return nil
+	}
+	mid := (lo + hi) / 2
+	h := newNode(sorted[mid])
+	h.Left = buildBalanced(sorted, lo, mid-1, level+1, redLevel)
+	h.Right = buildBalanced(sorted, mid+1, hi, level+1, redLevel)
+	if h.Left != nil {
+		h.Left.parent = h
+		h.count += h.Left.count
+	}
+	if h.Right != nil {
+		h.Right.parent = h
+		h.count += h.Right.count
+	}
+	h.Black = level != redLevel
+	return h.walkUpRot23()
+}
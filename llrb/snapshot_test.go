@@ -0,0 +1,119 @@
+// Copyright 2010 Petar Maymounkov. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package llrb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+)
+
+type sortInt int
+
+func (a sortInt) Less(b Item) bool { return a < b.(sortInt) }
+
+func sortIntSeq(n int) func(yield func(Item) bool) {
+	return func(yield func(Item) bool) {
+		for i := 0; i < n; i++ {
+			if !yield(sortInt(i)) {
+				return
+			}
+		}
+	}
+}
+
+// blackHeight returns the black-height of the subtree rooted at h (the
+// number of black links on any root-to-nil path), or -1 if the subtree
+// violates the invariant that every such path has the same black-height, or
+// if a red link leans right.
+func blackHeight(h *Node) int {
+	if h == nil {
+		return 1
+	}
+	if h.Right.isRed() {
+		return -1
+	}
+	left := blackHeight(h.Left)
+	if left == -1 {
+		return -1
+	}
+	right := blackHeight(h.Right)
+	if right == -1 || right != left {
+		return -1
+	}
+	if h.Black {
+		return left + 1
+	}
+	return left
+}
+
+func TestBulkLoadSortedBlackHeight(t *testing.T) {
+	for n := 0; n <= 40; n++ {
+		tree := BulkLoadSorted(sortIntSeq(n))
+		if tree.root != nil && !tree.root.Black {
+			t.Fatalf("n=%d: root is not black", n)
+		}
+		if bh := blackHeight(tree.root); bh == -1 {
+			t.Fatalf("n=%d: tree violates the LLRB black-height invariant", n)
+		}
+		if got := tree.Len(); got != n {
+			t.Fatalf("n=%d: Len() = %d, want %d", n, got, n)
+		}
+		for i := 0; i < n; i++ {
+			if !tree.Has(sortInt(i)) {
+				t.Fatalf("n=%d: missing element %d", n, i)
+			}
+		}
+		if n > 0 {
+			if got := tree.Min(); got != sortInt(0) {
+				t.Fatalf("n=%d: Min() = %v, want 0", n, got)
+			}
+			if got := tree.Max(); got != sortInt(n-1) {
+				t.Fatalf("n=%d: Max() = %v, want %d", n, got, n-1)
+			}
+		}
+	}
+}
+
+func encodeSortInt(w io.Writer, item Item) error {
+	return binary.Write(w, binary.BigEndian, int64(item.(sortInt)))
+}
+
+func decodeSortInt(r io.Reader) (Item, error) {
+	var v int64
+	if err := binary.Read(r, binary.BigEndian, &v); err != nil {
+		return nil, err
+	}
+	return sortInt(v), nil
+}
+
+func TestMarshalUnmarshalBinaryRoundTrip(t *testing.T) {
+	tree := New()
+	for _, v := range []int{5, 1, 9, 3, 7, 2, 8, 4, 6, 0} {
+		tree.ReplaceOrInsert(sortInt(v))
+	}
+
+	var buf bytes.Buffer
+	if err := tree.MarshalBinary(&buf, encodeSortInt); err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+
+	got, err := UnmarshalBinary(&buf, decodeSortInt)
+	if err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+	if got.Len() != tree.Len() {
+		t.Fatalf("Len() = %d, want %d", got.Len(), tree.Len())
+	}
+	for i := 0; i < 10; i++ {
+		if !got.Has(sortInt(i)) {
+			t.Fatalf("round-tripped tree missing element %d", i)
+		}
+	}
+	if bh := blackHeight(got.root); bh == -1 {
+		t.Fatalf("round-tripped tree violates the LLRB black-height invariant")
+	}
+}